@@ -2,6 +2,7 @@ package types
 
 import (
 	"database/sql"
+	"fmt"
 	"strconv"
 	"time"
 
@@ -9,6 +10,24 @@ import (
 	"github.com/tendermint/tendermint/crypto"
 )
 
+// DoNotModifyDesc is the sentinel value cosmos-sdk's MsgEditValidator uses
+// to signal that a description field should keep its previous value
+// instead of being overwritten
+const DoNotModifyDesc = "[do-not-modify]"
+
+const (
+	// MaxMonikerLength is the maximum length of a validator's moniker
+	MaxMonikerLength = 70
+	// MaxIdentityLength is the maximum length of a validator's identity
+	MaxIdentityLength = 3000
+	// MaxWebsiteLength is the maximum length of a validator's website
+	MaxWebsiteLength = 140
+	// MaxSecurityContactLength is the maximum length of a validator's security contact
+	MaxSecurityContactLength = 140
+	// MaxDetailsLength is the maximum length of a validator's details
+	MaxDetailsLength = 280
+)
+
 // StakingPoolRow represents a single row inside the staking_pool table
 type StakingPoolRow struct {
 	BondedTokens    int64     `db:"bonded_tokens"`
@@ -64,20 +83,16 @@ type ValidatorInfoRow struct {
 	ConsAddress         string `db:"consensus_address"`
 	ValAddress          string `db:"operator_address"`
 	SelfDelegateAddress string `db:"self_delegate_address"`
-	MaxChangeRate       string `db:"max_change_rate"`
-	MaxRate             string `db:"max_rate"`
 }
 
 // NewValidatorInfoRow allows to build a new ValidatorInfoRow
 func NewValidatorInfoRow(
-	consAddress string, valAddress string, selfDelegateAddress string, maxChangeRate string, maxRate string,
+	consAddress string, valAddress string, selfDelegateAddress string,
 ) ValidatorInfoRow {
 	return ValidatorInfoRow{
 		ConsAddress:         consAddress,
 		ValAddress:          valAddress,
 		SelfDelegateAddress: selfDelegateAddress,
-		MaxChangeRate:       maxChangeRate,
-		MaxRate:             maxRate,
 	}
 }
 
@@ -85,9 +100,7 @@ func NewValidatorInfoRow(
 func (v ValidatorInfoRow) Equal(w ValidatorInfoRow) bool {
 	return v.ConsAddress == w.ConsAddress &&
 		v.ValAddress == w.ValAddress &&
-		v.SelfDelegateAddress == w.SelfDelegateAddress &&
-		v.MaxRate == w.MaxRate &&
-		v.MaxChangeRate == w.MaxChangeRate
+		v.SelfDelegateAddress == w.SelfDelegateAddress
 }
 
 // ________________________________________________
@@ -101,11 +114,14 @@ type ValidatorData struct {
 	SelfDelegateAddress string `db:"self_delegate_address"`
 	MaxRate             string `db:"max_rate"`
 	MaxChangeRate       string `db:"max_change_rate"`
+	Status              int    `db:"status"`
+	Jailed              bool   `db:"jailed"`
 }
 
 // NewValidatorData allows to build a new ValidatorData
 func NewValidatorData(
 	consAddress, valAddress, consPubKey string, selfDelegateAddress string, maxRate string, maxChangeRate string,
+	status int, jailed bool,
 ) ValidatorData {
 	return ValidatorData{
 		ConsAddress:         consAddress,
@@ -114,6 +130,8 @@ func NewValidatorData(
 		SelfDelegateAddress: selfDelegateAddress,
 		MaxRate:             maxRate,
 		MaxChangeRate:       maxChangeRate,
+		Status:              status,
+		Jailed:              jailed,
 	}
 }
 
@@ -166,6 +184,16 @@ func (v ValidatorData) GetMaxRate() *sdk.Dec {
 	return &result
 }
 
+// GetStatus returns the latest known bonding status of the validator
+func (v ValidatorData) GetStatus() sdk.BondStatus {
+	return sdk.BondStatus(v.Status)
+}
+
+// IsJailed tells whether the validator is currently jailed
+func (v ValidatorData) IsJailed() bool {
+	return v.Jailed
+}
+
 // ________________________________________________
 
 // ValidatorUptimeRow represents a single row of the validator_uptime table
@@ -306,44 +334,76 @@ func (v ValidatorReDelegationRow) Equal(w ValidatorReDelegationRow) bool {
 		v.CompletionTime.Equal(w.CompletionTime)
 }
 
-// ValidatorCommission represents a single row of the
-// validator_commission database table
-type ValidatorCommission struct {
+// ValidatorCommissionLimitsRow represents a single row of the
+// validator_commission_limits table. Unlike the current commission rate,
+// MaxRate and MaxChangeRate are fixed at validator creation, so this table
+// holds a single immutable row per validator instead of a time series.
+type ValidatorCommissionLimitsRow struct {
+	OperatorAddress string `db:"operator_address"`
+	MaxRate         string `db:"max_rate"`
+	MaxChangeRate   string `db:"max_change_rate"`
+}
+
+// NewValidatorCommissionLimitsRow allows to easily build a new
+// ValidatorCommissionLimitsRow instance
+func NewValidatorCommissionLimitsRow(operatorAddress string, maxRate string, maxChangeRate string) ValidatorCommissionLimitsRow {
+	return ValidatorCommissionLimitsRow{
+		OperatorAddress: operatorAddress,
+		MaxRate:         maxRate,
+		MaxChangeRate:   maxChangeRate,
+	}
+}
+
+// Equal tells whether v and w represent the same rows
+func (v ValidatorCommissionLimitsRow) Equal(w ValidatorCommissionLimitsRow) bool {
+	return v.OperatorAddress == w.OperatorAddress &&
+		v.MaxRate == w.MaxRate &&
+		v.MaxChangeRate == w.MaxChangeRate
+}
+
+// ValidatorCommissionRow represents a single row of the
+// validator_commission database table. It tracks the current commission
+// Rate together with the UpdateTime at which it last changed, so that the
+// exact commission in effect at any past height can be reconstructed.
+type ValidatorCommissionRow struct {
 	OperatorAddress   string         `db:"operator_address"`
-	Timestamp         time.Time      `db:"timestamp"`
-	Commission        sql.NullString `db:"commission"`
+	Rate              sql.NullString `db:"rate"`
 	MinSelfDelegation sql.NullString `db:"min_self_delegation"`
+	UpdateTime        time.Time      `db:"update_time"`
 	Height            int64          `db:"height"`
+	Timestamp         time.Time      `db:"timestamp"`
 }
 
-// NewValidatorCommission allows to easily build a new
-// ValidatorCommission instance
-func NewValidatorCommission(
-	operatorAddress string, commission string, minSelfDelegation string, height int64, timestamp time.Time,
-) ValidatorCommission {
-	return ValidatorCommission{
+// NewValidatorCommissionRow allows to easily build a new
+// ValidatorCommissionRow instance
+func NewValidatorCommissionRow(
+	operatorAddress string, rate string, minSelfDelegation string, updateTime time.Time, height int64, timestamp time.Time,
+) ValidatorCommissionRow {
+	return ValidatorCommissionRow{
 		OperatorAddress:   operatorAddress,
-		Timestamp:         timestamp,
-		Commission:        sql.NullString{String: commission, Valid: true},
+		Rate:              sql.NullString{String: rate, Valid: true},
 		MinSelfDelegation: sql.NullString{String: minSelfDelegation, Valid: true},
+		UpdateTime:        updateTime,
 		Height:            height,
+		Timestamp:         timestamp,
 	}
 }
 
 // Equal tells whether v and w represent the same rows
-func (v ValidatorCommission) Equal(w ValidatorCommission) bool {
+func (v ValidatorCommissionRow) Equal(w ValidatorCommissionRow) bool {
 	return v.OperatorAddress == w.OperatorAddress &&
-		v.Timestamp.Equal(w.Timestamp) &&
-		v.Commission == w.Commission &&
+		v.Rate == w.Rate &&
 		v.MinSelfDelegation == w.MinSelfDelegation &&
-		v.Height == w.Height
+		v.UpdateTime.Equal(w.UpdateTime) &&
+		v.Height == w.Height &&
+		v.Timestamp.Equal(w.Timestamp)
 }
 
 //ValidatorDelegation store the return of validator_delegation_shares
 type ValidatorDelegationSharesRow struct {
 	OperatorAddress  string    `db:"operator_address"`
 	DelegatorAddress string    `db:"delegator_address"`
-	Shares           float64   `db:"shares"`
+	Shares           string    `db:"shares"`
 	Timestamp        time.Time `db:"timestamp"`
 	Height           int64     `db:"height"`
 }
@@ -352,25 +412,40 @@ type ValidatorDelegationSharesRow struct {
 func (v ValidatorDelegationSharesRow) Equal(w ValidatorDelegationSharesRow) bool {
 	return v.OperatorAddress == w.OperatorAddress &&
 		v.DelegatorAddress == w.DelegatorAddress &&
-		v.Shares == w.Shares &&
+		v.GetShares().Equal(w.GetShares()) &&
 		v.Timestamp.Equal(w.Timestamp) &&
 		v.Height == w.Height
 }
 
 // NewValidatorDelegationSharesRow make a new instance of ValidatorDelegationSharesRow
 func NewValidatorDelegationSharesRow(
-	operatorAddress string, delegatorAddress string, shares float64,
+	operatorAddress string, delegatorAddress string, shares sdk.Dec,
 	timestamp time.Time, height int64,
 ) ValidatorDelegationSharesRow {
 	return ValidatorDelegationSharesRow{
 		OperatorAddress:  operatorAddress,
 		DelegatorAddress: delegatorAddress,
-		Shares:           shares,
+		Shares:           shares.String(),
 		Timestamp:        timestamp,
 		Height:           height,
 	}
 }
 
+// GetShares parses the stored Shares column back into an sdk.Dec
+func (v ValidatorDelegationSharesRow) GetShares() sdk.Dec {
+	shares, err := sdk.NewDecFromStr(v.Shares)
+	if err != nil {
+		panic(err)
+	}
+	return shares
+}
+
+// StakeAmount computes the delegator's current token stake using the
+// validator's exchange rate, tokens / delegator shares
+func (v ValidatorDelegationSharesRow) StakeAmount(validatorTokens, validatorShares sdk.Dec) sdk.Int {
+	return validatorTokens.Mul(v.GetShares()).Quo(validatorShares).TruncateInt()
+}
+
 // ValidatorVotingPowerRow represent a row inside the validator_voting_power database table
 type ValidatorVotingPowerRow struct {
 	ConsensusAddress string `db:"consensus_address"`
@@ -400,6 +475,101 @@ func NewValidatorVotingPowerRow(
 
 //________________________________________________________________
 
+// ValidatorStatusRow represents a single row of the validator_status table,
+// storing the validator's bonding status history
+type ValidatorStatusRow struct {
+	ConsAddress string    `db:"consensus_address"`
+	Status      int       `db:"status"`
+	Jailed      bool      `db:"jailed"`
+	Height      int64     `db:"height"`
+	Timestamp   time.Time `db:"timestamp"`
+}
+
+// NewValidatorStatusRow allows to build a new ValidatorStatusRow
+func NewValidatorStatusRow(consAddress string, status int, jailed bool, height int64, timestamp time.Time) ValidatorStatusRow {
+	return ValidatorStatusRow{
+		ConsAddress: consAddress,
+		Status:      status,
+		Jailed:      jailed,
+		Height:      height,
+		Timestamp:   timestamp,
+	}
+}
+
+// Equal tells whether v and w represent the same rows
+func (v ValidatorStatusRow) Equal(w ValidatorStatusRow) bool {
+	return v.ConsAddress == w.ConsAddress &&
+		v.Status == w.Status &&
+		v.Jailed == w.Jailed &&
+		v.Height == w.Height &&
+		v.Timestamp.Equal(w.Timestamp)
+}
+
+// ValidatorBondRow represents a single row of the validator_bond table,
+// tracking the height at which a validator bonded and, if it has started
+// unbonding, the height and completion time of that process
+type ValidatorBondRow struct {
+	ConsAddress             string    `db:"consensus_address"`
+	BondHeight              int64     `db:"bond_height"`
+	UnbondingHeight         int64     `db:"unbonding_height"`
+	UnbondingCompletionTime time.Time `db:"unbonding_completion_time"`
+}
+
+// NewValidatorBondRow allows to build a new ValidatorBondRow
+func NewValidatorBondRow(
+	consAddress string, bondHeight int64, unbondingHeight int64, unbondingCompletionTime time.Time,
+) ValidatorBondRow {
+	return ValidatorBondRow{
+		ConsAddress:             consAddress,
+		BondHeight:              bondHeight,
+		UnbondingHeight:         unbondingHeight,
+		UnbondingCompletionTime: unbondingCompletionTime,
+	}
+}
+
+// Equal tells whether v and w represent the same rows
+func (v ValidatorBondRow) Equal(w ValidatorBondRow) bool {
+	return v.ConsAddress == w.ConsAddress &&
+		v.BondHeight == w.BondHeight &&
+		v.UnbondingHeight == w.UnbondingHeight &&
+		v.UnbondingCompletionTime.Equal(w.UnbondingCompletionTime)
+}
+
+// ValidatorStakeRow represents a single row of the validator_stake table,
+// storing the validator's total bonded tokens and delegator shares at a
+// given height
+type ValidatorStakeRow struct {
+	ConsAddress     string    `db:"consensus_address"`
+	Tokens          string    `db:"tokens"`
+	DelegatorShares string    `db:"delegator_shares"`
+	Height          int64     `db:"height"`
+	Timestamp       time.Time `db:"timestamp"`
+}
+
+// NewValidatorStakeRow allows to build a new ValidatorStakeRow
+func NewValidatorStakeRow(
+	consAddress string, tokens string, delegatorShares string, height int64, timestamp time.Time,
+) ValidatorStakeRow {
+	return ValidatorStakeRow{
+		ConsAddress:     consAddress,
+		Tokens:          tokens,
+		DelegatorShares: delegatorShares,
+		Height:          height,
+		Timestamp:       timestamp,
+	}
+}
+
+// Equal tells whether v and w represent the same rows
+func (v ValidatorStakeRow) Equal(w ValidatorStakeRow) bool {
+	return v.ConsAddress == w.ConsAddress &&
+		v.Tokens == w.Tokens &&
+		v.DelegatorShares == w.DelegatorShares &&
+		v.Height == w.Height &&
+		v.Timestamp.Equal(w.Timestamp)
+}
+
+//________________________________________________________________
+
 // ValidatorDescriptionRow represent a row in validator_description
 type ValidatorDescriptionRow struct {
 	ValAddress      string         `db:"operator_address"`
@@ -446,3 +616,173 @@ func (w ValidatorDescriptionRow) Equals(v ValidatorDescriptionRow) bool {
 		v.Height == w.Height &&
 		v.Timestamp.Equal(w.Timestamp)
 }
+
+// Validate enforces the same field length constraints cosmos-sdk's
+// staking module places on a validator's Description
+func (v ValidatorDescriptionRow) Validate() error {
+	if len(v.Moniker.String) > MaxMonikerLength {
+		return fmt.Errorf("invalid moniker length, got %d, max is %d", len(v.Moniker.String), MaxMonikerLength)
+	}
+	if len(v.Identity.String) > MaxIdentityLength {
+		return fmt.Errorf("invalid identity length, got %d, max is %d", len(v.Identity.String), MaxIdentityLength)
+	}
+	if len(v.Website.String) > MaxWebsiteLength {
+		return fmt.Errorf("invalid website length, got %d, max is %d", len(v.Website.String), MaxWebsiteLength)
+	}
+	if len(v.SecurityContact.String) > MaxSecurityContactLength {
+		return fmt.Errorf("invalid security contact length, got %d, max is %d", len(v.SecurityContact.String), MaxSecurityContactLength)
+	}
+	if len(v.Details.String) > MaxDetailsLength {
+		return fmt.Errorf("invalid details length, got %d, max is %d", len(v.Details.String), MaxDetailsLength)
+	}
+	return nil
+}
+
+// Merge replaces any field of v that is equal to DoNotModifyDesc with the
+// corresponding field of previous, reconstructing the intended
+// description from a partial MsgEditValidator update
+func (v ValidatorDescriptionRow) Merge(previous ValidatorDescriptionRow) ValidatorDescriptionRow {
+	if v.Moniker.String == DoNotModifyDesc {
+		v.Moniker = previous.Moniker
+	}
+	if v.Identity.String == DoNotModifyDesc {
+		v.Identity = previous.Identity
+	}
+	if v.Website.String == DoNotModifyDesc {
+		v.Website = previous.Website
+	}
+	if v.SecurityContact.String == DoNotModifyDesc {
+		v.SecurityContact = previous.SecurityContact
+	}
+	if v.Details.String == DoNotModifyDesc {
+		v.Details = previous.Details
+	}
+	return v
+}
+
+// PrepareValidatorDescriptionUpdate prepares an incoming validator_description
+// row for persistence: it resolves the "[do-not-modify]" sentinel fields
+// against the previously stored description, then enforces cosmos-sdk's
+// length constraints on the result. ImportStakingGenesis calls it for every
+// genesis validator; no MsgEditValidator handler in this package calls it.
+func PrepareValidatorDescriptionUpdate(incoming ValidatorDescriptionRow, previous ValidatorDescriptionRow) (ValidatorDescriptionRow, error) {
+	merged := incoming.Merge(previous)
+	if err := merged.Validate(); err != nil {
+		return ValidatorDescriptionRow{}, err
+	}
+	return merged, nil
+}
+
+//________________________________________________________________
+
+// ValidatorRewardAccumRow represents a single row of the
+// validator_reward_accum table. It stores, for a given validator and
+// period, the cumulative per-share reward ratio accumulated up to and
+// including that period (the sum over all past periods of
+// fees_collected / delegator_shares). This follows the F1-style lazy
+// distribution model: rewards are never pushed to delegators directly,
+// they are computed on demand from the accumulator difference between
+// two periods.
+type ValidatorRewardAccumRow struct {
+	OperatorAddress       string    `db:"operator_address"`
+	CumulativeRewardRatio DbCoins   `db:"cumulative_reward_ratio"`
+	Period                int64     `db:"period"`
+	Height                int64     `db:"height"`
+	Timestamp             time.Time `db:"timestamp"`
+}
+
+// NewValidatorRewardAccumRow allows to build a new ValidatorRewardAccumRow
+func NewValidatorRewardAccumRow(
+	operatorAddress string, cumulativeRewardRatio DbCoins, period int64, height int64, timestamp time.Time,
+) ValidatorRewardAccumRow {
+	return ValidatorRewardAccumRow{
+		OperatorAddress:       operatorAddress,
+		CumulativeRewardRatio: cumulativeRewardRatio,
+		Period:                period,
+		Height:                height,
+		Timestamp:             timestamp,
+	}
+}
+
+// Equal tells whether v and w represent the same rows
+func (v ValidatorRewardAccumRow) Equal(w ValidatorRewardAccumRow) bool {
+	return v.OperatorAddress == w.OperatorAddress &&
+		v.CumulativeRewardRatio.Equal(w.CumulativeRewardRatio) &&
+		v.Period == w.Period &&
+		v.Height == w.Height &&
+		v.Timestamp.Equal(w.Timestamp)
+}
+
+// DelegatorStartingInfoRow represents a single row of the
+// delegator_starting_info table. It is a snapshot taken whenever a
+// delegation begins or is modified (delegate, undelegate, redelegate),
+// recording the validator's period and the delegator's stake at that
+// moment so that rewards accrued before the snapshot are not double
+// counted.
+type DelegatorStartingInfoRow struct {
+	DelegatorAddress string  `db:"delegator_address"`
+	OperatorAddress  string  `db:"operator_address"`
+	PreviousPeriod   int64   `db:"previous_period"`
+	Stake            sdk.Dec `db:"stake"`
+	Height           int64   `db:"height"`
+}
+
+// NewDelegatorStartingInfoRow allows to build a new DelegatorStartingInfoRow
+func NewDelegatorStartingInfoRow(
+	delegatorAddress string, operatorAddress string, previousPeriod int64, stake sdk.Dec, height int64,
+) DelegatorStartingInfoRow {
+	return DelegatorStartingInfoRow{
+		DelegatorAddress: delegatorAddress,
+		OperatorAddress:  operatorAddress,
+		PreviousPeriod:   previousPeriod,
+		Stake:            stake,
+		Height:           height,
+	}
+}
+
+// Equal tells whether v and w represent the same rows
+func (v DelegatorStartingInfoRow) Equal(w DelegatorStartingInfoRow) bool {
+	return v.DelegatorAddress == w.DelegatorAddress &&
+		v.OperatorAddress == w.OperatorAddress &&
+		v.PreviousPeriod == w.PreviousPeriod &&
+		v.Stake.Equal(w.Stake) &&
+		v.Height == w.Height
+}
+
+// ValidatorOutstandingRewardsRow represents a single row of the
+// validator_outstanding_rewards table, storing the total rewards a
+// validator has collected but not yet distributed to its delegators
+type ValidatorOutstandingRewardsRow struct {
+	OperatorAddress string  `db:"operator_address"`
+	Rewards         DbCoins `db:"rewards"`
+	Height          int64   `db:"height"`
+}
+
+// NewValidatorOutstandingRewardsRow allows to build a new
+// ValidatorOutstandingRewardsRow
+func NewValidatorOutstandingRewardsRow(operatorAddress string, rewards DbCoins, height int64) ValidatorOutstandingRewardsRow {
+	return ValidatorOutstandingRewardsRow{
+		OperatorAddress: operatorAddress,
+		Rewards:         rewards,
+		Height:          height,
+	}
+}
+
+// Equal tells whether v and w represent the same rows
+func (v ValidatorOutstandingRewardsRow) Equal(w ValidatorOutstandingRewardsRow) bool {
+	return v.OperatorAddress == w.OperatorAddress &&
+		v.Rewards.Equal(w.Rewards) &&
+		v.Height == w.Height
+}
+
+// ComputeDelegationRewards returns the rewards accrued by a delegation
+// between the period it started accumulating from (startAccum) and the
+// period up to which rewards are being calculated (endAccum). The
+// invariant relied upon is that a new period is opened every time the
+// validator's DelegatorShares changes (delegate/undelegate/slash), so
+// the difference between the two cumulative ratios reflects exactly the
+// rewards accrued while the delegator held `start.Stake` shares.
+func ComputeDelegationRewards(start DelegatorStartingInfoRow, endAccum, startAccum ValidatorRewardAccumRow) sdk.DecCoins {
+	difference := endAccum.CumulativeRewardRatio.DecCoins().Sub(startAccum.CumulativeRewardRatio.DecCoins())
+	return difference.MulDecTruncate(start.Stake)
+}
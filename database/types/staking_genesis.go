@@ -0,0 +1,298 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+// StakingParamsRow represents a single row of the staking_params table,
+// keyed by height so that param-change proposals can be tracked over time
+type StakingParamsRow struct {
+	UnbondingTime string `db:"unbonding_time"`
+	MaxValidators uint32 `db:"max_validators"`
+	MaxEntries    uint32 `db:"max_entries"`
+	BondDenom     string `db:"bond_denom"`
+	Height        int64  `db:"height"`
+}
+
+// NewStakingParamsRow allows to build a new StakingParamsRow
+func NewStakingParamsRow(unbondingTime string, maxValidators uint32, maxEntries uint32, bondDenom string, height int64) StakingParamsRow {
+	return StakingParamsRow{
+		UnbondingTime: unbondingTime,
+		MaxValidators: maxValidators,
+		MaxEntries:    maxEntries,
+		BondDenom:     bondDenom,
+		Height:        height,
+	}
+}
+
+// Equal tells whether v and w represent the same rows
+func (v StakingParamsRow) Equal(w StakingParamsRow) bool {
+	return v.UnbondingTime == w.UnbondingTime &&
+		v.MaxValidators == w.MaxValidators &&
+		v.MaxEntries == w.MaxEntries &&
+		v.BondDenom == w.BondDenom &&
+		v.Height == w.Height
+}
+
+// genesisPubKey mirrors the proto Any encoding the JSON marshaler uses for
+// a validator's consensus_pubkey (e.g. {"@type":"/cosmos.crypto.ed25519.PubKey","key":"..."})
+type genesisPubKey struct {
+	Type string `json:"@type"`
+	Key  string `json:"key"`
+}
+
+// PubKey decodes the base64-encoded key bytes into a tendermint ed25519 PubKey
+func (p genesisPubKey) PubKey() (crypto.PubKey, error) {
+	keyBytes, err := base64.StdEncoding.DecodeString(p.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode consensus pubkey: %s", err)
+	}
+
+	return ed25519.PubKey(keyBytes), nil
+}
+
+// bondStatusFromGenesis maps the string enum cosmos-sdk uses for a
+// validator's Status in genesis JSON (e.g. "BOND_STATUS_BONDED") to the
+// int value stored in ValidatorStatusRow/ValidatorData
+func bondStatusFromGenesis(status string) int {
+	switch status {
+	case "BOND_STATUS_BONDED":
+		return int(sdk.Bonded)
+	case "BOND_STATUS_UNBONDING":
+		return int(sdk.Unbonding)
+	default:
+		return int(sdk.Unbonded)
+	}
+}
+
+// StakingGenesisState mirrors the shape of cosmos-sdk's staking
+// GenesisState as it appears under app_state.staking inside a genesis
+// file, using plain strings for the JSON encoded Int/Dec fields
+type StakingGenesisState struct {
+	Params struct {
+		UnbondingTime string `json:"unbonding_time"`
+		MaxValidators uint32 `json:"max_validators"`
+		MaxEntries    uint32 `json:"max_entries"`
+		BondDenom     string `json:"bond_denom"`
+	} `json:"params"`
+	Pool struct {
+		NotBondedTokens string `json:"not_bonded_tokens"`
+		BondedTokens    string `json:"bonded_tokens"`
+	} `json:"pool"`
+	Validators []struct {
+		OperatorAddress string        `json:"operator_address"`
+		ConsensusPubkey genesisPubKey `json:"consensus_pubkey"`
+		Jailed          bool          `json:"jailed"`
+		Status          string        `json:"status"`
+		Tokens          string        `json:"tokens"`
+		DelegatorShares string        `json:"delegator_shares"`
+		Description     struct {
+			Moniker         string `json:"moniker"`
+			Identity        string `json:"identity"`
+			Website         string `json:"website"`
+			SecurityContact string `json:"security_contact"`
+			Details         string `json:"details"`
+		} `json:"description"`
+		UnbondingHeight int64  `json:"unbonding_height,string"`
+		UnbondingTime   string `json:"unbonding_time"`
+		Commission      struct {
+			CommissionRates struct {
+				Rate          string `json:"rate"`
+				MaxRate       string `json:"max_rate"`
+				MaxChangeRate string `json:"max_change_rate"`
+			} `json:"commission_rates"`
+			UpdateTime string `json:"update_time"`
+		} `json:"commission"`
+		MinSelfDelegation string `json:"min_self_delegation"`
+	} `json:"validators"`
+	Delegations []struct {
+		DelegatorAddress string `json:"delegator_address"`
+		ValidatorAddress string `json:"validator_address"`
+		Shares           string `json:"shares"`
+	} `json:"delegations"`
+	UnbondingDelegations []struct {
+		DelegatorAddress string `json:"delegator_address"`
+		ValidatorAddress string `json:"validator_address"`
+		Entries          []struct {
+			CompletionTime string `json:"completion_time"`
+			Balance        string `json:"balance"`
+		} `json:"entries"`
+	} `json:"unbonding_delegations"`
+	Redelegations []struct {
+		DelegatorAddress    string `json:"delegator_address"`
+		ValidatorSrcAddress string `json:"validator_src_address"`
+		ValidatorDstAddress string `json:"validator_dst_address"`
+		Entries             []struct {
+			CompletionTime string `json:"completion_time"`
+			Balance        string `json:"balance"`
+		} `json:"entries"`
+	} `json:"redelegations"`
+}
+
+// StakingGenesisRows groups together every row that needs to be persisted
+// in order to materialize the staking module's genesis state
+type StakingGenesisRows struct {
+	Pool                  StakingPoolRow
+	Params                StakingParamsRow
+	Validators            []ValidatorRow
+	ValidatorInfos        []ValidatorInfoRow
+	ValidatorDescriptions []ValidatorDescriptionRow
+	ValidatorCommissions  []ValidatorCommissionRow
+	CommissionLimits      []ValidatorCommissionLimitsRow
+	ValidatorStatuses     []ValidatorStatusRow
+	DelegationShares      []ValidatorDelegationSharesRow
+	UnbondingDelegations  []ValidatorUnbondingDelegationRow
+	Redelegations         []ValidatorReDelegationRow
+}
+
+// ImportStakingGenesis parses the app_state.staking object of genesisDoc
+// and returns the rows needed to materialize the initial staking pool,
+// validators, delegations, unbonding delegations and redelegations, so
+// that indexing a chain from height 1 does not miss genesis-only state
+func ImportStakingGenesis(genesisDoc json.RawMessage) (StakingGenesisRows, error) {
+	var doc struct {
+		GenesisTime string `json:"genesis_time"`
+		AppState    struct {
+			Staking StakingGenesisState `json:"staking"`
+		} `json:"app_state"`
+	}
+	if err := json.Unmarshal(genesisDoc, &doc); err != nil {
+		return StakingGenesisRows{}, fmt.Errorf("failed to parse genesis doc: %s", err)
+	}
+
+	genesisTime, err := time.Parse(time.RFC3339, doc.GenesisTime)
+	if err != nil {
+		return StakingGenesisRows{}, fmt.Errorf("failed to parse genesis time: %s", err)
+	}
+
+	staking := doc.AppState.Staking
+
+	var rows StakingGenesisRows
+	rows.Params = NewStakingParamsRow(
+		staking.Params.UnbondingTime, staking.Params.MaxValidators, staking.Params.MaxEntries, staking.Params.BondDenom, 1,
+	)
+
+	// consAddresses maps each validator's operator address to its derived
+	// consensus address, so that rows keyed by operator address in the raw
+	// genesis JSON (unbonding delegations, redelegations) can be rewritten
+	// to reference the same consensus_address used by ValidatorRow
+	consAddresses := make(map[string]string, len(staking.Validators))
+
+	for _, val := range staking.Validators {
+		valAddr, err := sdk.ValAddressFromBech32(val.OperatorAddress)
+		if err != nil {
+			return StakingGenesisRows{}, fmt.Errorf("failed to parse validator operator address: %s", err)
+		}
+		selfDelegateAddress := sdk.AccAddress(valAddr.Bytes()).String()
+
+		pubKey, err := val.ConsensusPubkey.PubKey()
+		if err != nil {
+			return StakingGenesisRows{}, err
+		}
+		consAddress := sdk.GetConsAddress(pubKey).String()
+		consPubKey, err := sdk.Bech32ifyPubKey(sdk.Bech32PubKeyTypeConsPub, pubKey)
+		if err != nil {
+			return StakingGenesisRows{}, fmt.Errorf("failed to encode consensus pubkey: %s", err)
+		}
+		consAddresses[val.OperatorAddress] = consAddress
+
+		commissionUpdateTime, err := time.Parse(time.RFC3339, val.Commission.UpdateTime)
+		if err != nil {
+			return StakingGenesisRows{}, fmt.Errorf("failed to parse commission update time: %s", err)
+		}
+
+		description, err := PrepareValidatorDescriptionUpdate(NewValidatorDescriptionRow(
+			val.OperatorAddress, val.Description.Moniker, val.Description.Identity, val.Description.Website,
+			val.Description.SecurityContact, val.Description.Details, 1, genesisTime,
+		), ValidatorDescriptionRow{})
+		if err != nil {
+			return StakingGenesisRows{}, fmt.Errorf("invalid validator description: %s", err)
+		}
+
+		rows.Validators = append(rows.Validators, NewValidatorRow(consAddress, consPubKey))
+		rows.ValidatorInfos = append(rows.ValidatorInfos, NewValidatorInfoRow(consAddress, val.OperatorAddress, selfDelegateAddress))
+		rows.ValidatorDescriptions = append(rows.ValidatorDescriptions, description)
+		rows.ValidatorStatuses = append(rows.ValidatorStatuses, NewValidatorStatusRow(
+			consAddress, bondStatusFromGenesis(val.Status), val.Jailed, 1, genesisTime,
+		))
+		rows.ValidatorCommissions = append(rows.ValidatorCommissions, NewValidatorCommissionRow(
+			val.OperatorAddress, val.Commission.CommissionRates.Rate, val.MinSelfDelegation, commissionUpdateTime, 1, genesisTime,
+		))
+		rows.CommissionLimits = append(rows.CommissionLimits, NewValidatorCommissionLimitsRow(
+			val.OperatorAddress, val.Commission.CommissionRates.MaxRate, val.Commission.CommissionRates.MaxChangeRate,
+		))
+	}
+
+	for _, delegation := range staking.Delegations {
+		shares, err := sdk.NewDecFromStr(delegation.Shares)
+		if err != nil {
+			return StakingGenesisRows{}, fmt.Errorf("failed to parse delegation shares: %s", err)
+		}
+
+		rows.DelegationShares = append(rows.DelegationShares, NewValidatorDelegationSharesRow(
+			delegation.ValidatorAddress, delegation.DelegatorAddress, shares, genesisTime, 1,
+		))
+	}
+
+	for _, ubd := range staking.UnbondingDelegations {
+		consAddress, ok := consAddresses[ubd.ValidatorAddress]
+		if !ok {
+			return StakingGenesisRows{}, fmt.Errorf("unbonding delegation references unknown validator %s", ubd.ValidatorAddress)
+		}
+
+		for _, entry := range ubd.Entries {
+			completionTime, err := time.Parse(time.RFC3339, entry.CompletionTime)
+			if err != nil {
+				return StakingGenesisRows{}, fmt.Errorf("failed to parse unbonding delegation completion time: %s", err)
+			}
+
+			rows.UnbondingDelegations = append(rows.UnbondingDelegations, NewValidatorUnbondingDelegationRow(
+				consAddress, ubd.DelegatorAddress, NewDbCoin(staking.Params.BondDenom, entry.Balance),
+				completionTime, 1, genesisTime,
+			))
+		}
+	}
+
+	for _, red := range staking.Redelegations {
+		srcConsAddress, ok := consAddresses[red.ValidatorSrcAddress]
+		if !ok {
+			return StakingGenesisRows{}, fmt.Errorf("redelegation references unknown source validator %s", red.ValidatorSrcAddress)
+		}
+		dstConsAddress, ok := consAddresses[red.ValidatorDstAddress]
+		if !ok {
+			return StakingGenesisRows{}, fmt.Errorf("redelegation references unknown destination validator %s", red.ValidatorDstAddress)
+		}
+
+		for _, entry := range red.Entries {
+			completionTime, err := time.Parse(time.RFC3339, entry.CompletionTime)
+			if err != nil {
+				return StakingGenesisRows{}, fmt.Errorf("failed to parse redelegation completion time: %s", err)
+			}
+
+			rows.Redelegations = append(rows.Redelegations, NewValidatorReDelegationRow(
+				red.DelegatorAddress, srcConsAddress, dstConsAddress,
+				NewDbCoin(staking.Params.BondDenom, entry.Balance), 1, completionTime,
+			))
+		}
+	}
+
+	bondedTokens, err := strconv.ParseInt(staking.Pool.BondedTokens, 10, 64)
+	if err != nil {
+		return StakingGenesisRows{}, fmt.Errorf("failed to parse bonded tokens: %s", err)
+	}
+	notBondedTokens, err := strconv.ParseInt(staking.Pool.NotBondedTokens, 10, 64)
+	if err != nil {
+		return StakingGenesisRows{}, fmt.Errorf("failed to parse not bonded tokens: %s", err)
+	}
+	rows.Pool = NewStakingPoolRow(bondedTokens, notBondedTokens, 1, genesisTime)
+
+	return rows, nil
+}
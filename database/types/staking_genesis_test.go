@@ -0,0 +1,219 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+const testGenesisDoc = `{
+	"genesis_time": "2021-01-01T00:00:00Z",
+	"app_state": {
+		"staking": {
+			"params": {
+				"unbonding_time": "1814400s",
+				"max_validators": 100,
+				"max_entries": 7,
+				"bond_denom": "stake"
+			},
+			"pool": {
+				"not_bonded_tokens": "100",
+				"bonded_tokens": "900"
+			},
+			"validators": [
+				{
+					"operator_address": "cosmosvaloper1zyg3zyg3zyg3zyg3zyg3zyg3zyg3r242nxkznp",
+					"consensus_pubkey": {
+						"@type": "/cosmos.crypto.ed25519.PubKey",
+						"key": "IiIiIiIiIiIiIiIiIiIiIiIiIiIiIiIiIiIiIiIiu7s="
+					},
+					"jailed": false,
+					"status": "BOND_STATUS_BONDED",
+					"tokens": "900",
+					"delegator_shares": "900.000000000000000000",
+					"description": {
+						"moniker": "test-validator",
+						"identity": "",
+						"website": "",
+						"security_contact": "",
+						"details": ""
+					},
+					"unbonding_height": "0",
+					"unbonding_time": "1970-01-01T00:00:00Z",
+					"commission": {
+						"commission_rates": {
+							"rate": "0.100000000000000000",
+							"max_rate": "0.200000000000000000",
+							"max_change_rate": "0.010000000000000000"
+						},
+						"update_time": "2021-01-01T00:00:00Z"
+					},
+					"min_self_delegation": "1"
+				},
+				{
+					"operator_address": "cosmosvaloper1g3zyg3zyg3zyg3zyg3zyg3zyg3zyfhwamh7an9",
+					"consensus_pubkey": {
+						"@type": "/cosmos.crypto.ed25519.PubKey",
+						"key": "VVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVV7u4="
+					},
+					"jailed": false,
+					"status": "BOND_STATUS_BONDED",
+					"tokens": "500",
+					"delegator_shares": "500.000000000000000000",
+					"description": {
+						"moniker": "second-validator",
+						"identity": "",
+						"website": "",
+						"security_contact": "",
+						"details": ""
+					},
+					"unbonding_height": "0",
+					"unbonding_time": "1970-01-01T00:00:00Z",
+					"commission": {
+						"commission_rates": {
+							"rate": "0.050000000000000000",
+							"max_rate": "0.200000000000000000",
+							"max_change_rate": "0.010000000000000000"
+						},
+						"update_time": "2021-01-01T00:00:00Z"
+					},
+					"min_self_delegation": "1"
+				}
+			],
+			"delegations": [
+				{
+					"delegator_address": "cosmos1xvenxvenxvenxvenxvenxvenxveuenq97hjjr",
+					"validator_address": "cosmosvaloper1zyg3zyg3zyg3zyg3zyg3zyg3zyg3r242nxkznp",
+					"shares": "900.000000000000000000"
+				}
+			],
+			"unbonding_delegations": [
+				{
+					"delegator_address": "cosmos1xvenxvenxvenxvenxvenxvenxveuenq97hjjr",
+					"validator_address": "cosmosvaloper1zyg3zyg3zyg3zyg3zyg3zyg3zyg3r242nxkznp",
+					"entries": [
+						{
+							"completion_time": "2021-01-22T00:00:00Z",
+							"balance": "100"
+						}
+					]
+				}
+			],
+			"redelegations": [
+				{
+					"delegator_address": "cosmos1xvenxvenxvenxvenxvenxvenxveuenq97hjjr",
+					"validator_src_address": "cosmosvaloper1zyg3zyg3zyg3zyg3zyg3zyg3zyg3r242nxkznp",
+					"validator_dst_address": "cosmosvaloper1g3zyg3zyg3zyg3zyg3zyg3zyg3zyfhwamh7an9",
+					"entries": [
+						{
+							"completion_time": "2021-01-22T00:00:00Z",
+							"balance": "50"
+						}
+					]
+				}
+			]
+		}
+	}
+}`
+
+func TestImportStakingGenesis(t *testing.T) {
+	rows, err := ImportStakingGenesis([]byte(testGenesisDoc))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedGenesisTime := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if rows.Pool.BondedTokens != 900 || rows.Pool.NotBondedTokens != 100 {
+		t.Fatalf("unexpected pool: %+v", rows.Pool)
+	}
+	if !rows.Pool.Timestamp.Equal(expectedGenesisTime) {
+		t.Fatalf("expected pool timestamp %s, got %s", expectedGenesisTime, rows.Pool.Timestamp)
+	}
+
+	if len(rows.Validators) != 2 {
+		t.Fatalf("expected 2 validator rows, got %d", len(rows.Validators))
+	}
+
+	expectedConsAddress := "cosmosvalcons19fl8lcns7j7m7w8eng2r3pvenmskr4qsn7qhrk"
+	expectedSelfDelegate := "cosmos1zyg3zyg3zyg3zyg3zyg3zyg3zyg3r242kjzhlj"
+	expectedSecondConsAddress := "cosmosvalcons1ratu74hcrrrajgxhr4y7nsfpwaqmkkj3av8g93"
+
+	validator := rows.Validators[0]
+	if validator.ConsAddress != expectedConsAddress {
+		t.Errorf("expected consensus address %s, got %s", expectedConsAddress, validator.ConsAddress)
+	}
+
+	info := rows.ValidatorInfos[0]
+	if info.ConsAddress != expectedConsAddress {
+		t.Errorf("expected info consensus address %s, got %s", expectedConsAddress, info.ConsAddress)
+	}
+	if info.SelfDelegateAddress != expectedSelfDelegate {
+		t.Errorf("expected self delegate address %s, got %s", expectedSelfDelegate, info.SelfDelegateAddress)
+	}
+
+	status := rows.ValidatorStatuses[0]
+	if status.ConsAddress != expectedConsAddress {
+		t.Errorf("expected status consensus address %s, got %s", expectedConsAddress, status.ConsAddress)
+	}
+	if status.Jailed {
+		t.Errorf("expected validator not to be jailed")
+	}
+
+	commission := rows.ValidatorCommissions[0]
+	expectedUpdateTime := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	if !commission.UpdateTime.Equal(expectedUpdateTime) {
+		t.Errorf("expected commission update time %s, got %s", expectedUpdateTime, commission.UpdateTime)
+	}
+	if commission.Rate.String != "0.100000000000000000" {
+		t.Errorf("expected commission rate 0.1, got %s", commission.Rate.String)
+	}
+
+	limits := rows.CommissionLimits[0]
+	if limits.MaxRate != "0.200000000000000000" || limits.MaxChangeRate != "0.010000000000000000" {
+		t.Errorf("unexpected commission limits: %+v", limits)
+	}
+
+	if len(rows.DelegationShares) != 1 {
+		t.Fatalf("expected 1 delegation shares row, got %d", len(rows.DelegationShares))
+	}
+	if rows.DelegationShares[0].Shares != "900.000000000000000000" {
+		t.Errorf("expected delegation shares 900, got %s", rows.DelegationShares[0].Shares)
+	}
+
+	if len(rows.UnbondingDelegations) != 1 {
+		t.Fatalf("expected 1 unbonding delegation row, got %d", len(rows.UnbondingDelegations))
+	}
+	if rows.UnbondingDelegations[0].ConsensusAddress != expectedConsAddress {
+		t.Errorf("expected unbonding delegation to reference consensus address %s, got %s",
+			expectedConsAddress, rows.UnbondingDelegations[0].ConsensusAddress)
+	}
+
+	if len(rows.Redelegations) != 1 {
+		t.Fatalf("expected 1 redelegation row, got %d", len(rows.Redelegations))
+	}
+	redelegation := rows.Redelegations[0]
+	if redelegation.SrcValidatorAddress != expectedConsAddress {
+		t.Errorf("expected redelegation source consensus address %s, got %s", expectedConsAddress, redelegation.SrcValidatorAddress)
+	}
+	if redelegation.DstValidatorAddress != expectedSecondConsAddress {
+		t.Errorf("expected redelegation destination consensus address %s, got %s", expectedSecondConsAddress, redelegation.DstValidatorAddress)
+	}
+}
+
+func TestBondStatusFromGenesis(t *testing.T) {
+	testCases := []struct {
+		status   string
+		expected int
+	}{
+		{"BOND_STATUS_BONDED", 3},
+		{"BOND_STATUS_UNBONDING", 2},
+		{"BOND_STATUS_UNBONDED", 1},
+		{"BOND_STATUS_UNSPECIFIED", 1},
+	}
+
+	for _, tc := range testCases {
+		if got := bondStatusFromGenesis(tc.status); got != tc.expected {
+			t.Errorf("bondStatusFromGenesis(%s) = %d, expected %d", tc.status, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package types
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestValidatorDelegationSharesRow_StakeAmount(t *testing.T) {
+	testCases := []struct {
+		name            string
+		shares          sdk.Dec
+		validatorTokens sdk.Dec
+		validatorShares sdk.Dec
+		expected        sdk.Int
+	}{
+		{
+			name:            "1:1 exchange rate",
+			shares:          sdk.NewDec(50),
+			validatorTokens: sdk.NewDec(100),
+			validatorShares: sdk.NewDec(100),
+			expected:        sdk.NewInt(50),
+		},
+		{
+			name:            "exchange rate above 1 after rewards",
+			shares:          sdk.NewDec(50),
+			validatorTokens: sdk.NewDec(200),
+			validatorShares: sdk.NewDec(100),
+			expected:        sdk.NewInt(100),
+		},
+		{
+			name:            "truncates instead of rounding",
+			shares:          sdk.NewDec(1),
+			validatorTokens: sdk.NewDec(10),
+			validatorShares: sdk.NewDec(3),
+			expected:        sdk.NewInt(3), // 10/3 = 3.33...
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			row := NewValidatorDelegationSharesRow("cosmosvaloper1abc", "cosmos1abc", tc.shares, time.Now(), 1)
+			got := row.StakeAmount(tc.validatorTokens, tc.validatorShares)
+			if !got.Equal(tc.expected) {
+				t.Errorf("expected stake amount %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidatorDelegationSharesRow_GetShares(t *testing.T) {
+	row := NewValidatorDelegationSharesRow("cosmosvaloper1abc", "cosmos1abc", sdk.NewDec(42), time.Now(), 1)
+	if !row.GetShares().Equal(sdk.NewDec(42)) {
+		t.Errorf("expected shares 42, got %s", row.GetShares())
+	}
+}
+
+func TestComputeDelegationRewards(t *testing.T) {
+	const opAddr = "cosmosvaloper1abc"
+	const delAddr = "cosmos1abc"
+	now := time.Now()
+
+	startAccum := NewValidatorRewardAccumRow(
+		opAddr, DbCoins(sdk.NewDecCoins(sdk.NewDecCoinFromDec("stake", sdk.MustNewDecFromStr("0.5")))), 1, 50, now,
+	)
+	endAccum := NewValidatorRewardAccumRow(
+		opAddr, DbCoins(sdk.NewDecCoins(sdk.NewDecCoinFromDec("stake", sdk.NewDec(2)))), 2, 100, now,
+	)
+	start := NewDelegatorStartingInfoRow(delAddr, opAddr, 1, sdk.NewDec(10), 50)
+
+	// (2 - 0.5) * 10 = 15
+	expected := sdk.NewDecCoins(sdk.NewDecCoinFromDec("stake", sdk.NewDec(15)))
+
+	got := ComputeDelegationRewards(start, endAccum, startAccum)
+	if !got.IsEqual(expected) {
+		t.Errorf("expected rewards %s, got %s", expected, got)
+	}
+}
+
+func TestComputeDelegationRewards_NoAccrual(t *testing.T) {
+	const opAddr = "cosmosvaloper1abc"
+	const delAddr = "cosmos1abc"
+	now := time.Now()
+
+	ratio := DbCoins(sdk.NewDecCoins(sdk.NewDecCoinFromDec("stake", sdk.NewDec(1))))
+	startAccum := NewValidatorRewardAccumRow(opAddr, ratio, 1, 50, now)
+	endAccum := NewValidatorRewardAccumRow(opAddr, ratio, 1, 50, now)
+	start := NewDelegatorStartingInfoRow(delAddr, opAddr, 1, sdk.NewDec(10), 50)
+
+	got := ComputeDelegationRewards(start, endAccum, startAccum)
+	if !got.IsZero() {
+		t.Errorf("expected zero rewards when the accumulator has not moved, got %s", got)
+	}
+}
+
+func TestValidatorDescriptionRow_Validate(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name      string
+		row       ValidatorDescriptionRow
+		expectErr bool
+	}{
+		{
+			name:      "moniker at the max length is valid",
+			row:       NewValidatorDescriptionRow("cosmosvaloper1abc", strings.Repeat("a", MaxMonikerLength), "", "", "", "", 1, now),
+			expectErr: false,
+		},
+		{
+			name:      "moniker over the max length is invalid",
+			row:       NewValidatorDescriptionRow("cosmosvaloper1abc", strings.Repeat("a", MaxMonikerLength+1), "", "", "", "", 1, now),
+			expectErr: true,
+		},
+		{
+			name:      "details at the max length is valid",
+			row:       NewValidatorDescriptionRow("cosmosvaloper1abc", "", "", "", "", strings.Repeat("a", MaxDetailsLength), 1, now),
+			expectErr: false,
+		},
+		{
+			name:      "details over the max length is invalid",
+			row:       NewValidatorDescriptionRow("cosmosvaloper1abc", "", "", "", "", strings.Repeat("a", MaxDetailsLength+1), 1, now),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.row.Validate()
+			if tc.expectErr && err == nil {
+				t.Errorf("expected an error, got none")
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("expected no error, got %s", err)
+			}
+		})
+	}
+}
+
+func TestValidatorDescriptionRow_Merge(t *testing.T) {
+	now := time.Now()
+	previous := NewValidatorDescriptionRow("cosmosvaloper1abc", "old-moniker", "old-identity", "old-website", "old-contact", "old-details", 1, now)
+	incoming := NewValidatorDescriptionRow(
+		"cosmosvaloper1abc", "new-moniker", DoNotModifyDesc, DoNotModifyDesc, "new-contact", DoNotModifyDesc, 2, now,
+	)
+
+	merged := incoming.Merge(previous)
+
+	if merged.Moniker.String != "new-moniker" {
+		t.Errorf("expected moniker to be updated, got %s", merged.Moniker.String)
+	}
+	if merged.Identity.String != "old-identity" {
+		t.Errorf("expected identity to be kept from previous, got %s", merged.Identity.String)
+	}
+	if merged.Website.String != "old-website" {
+		t.Errorf("expected website to be kept from previous, got %s", merged.Website.String)
+	}
+	if merged.SecurityContact.String != "new-contact" {
+		t.Errorf("expected security contact to be updated, got %s", merged.SecurityContact.String)
+	}
+	if merged.Details.String != "old-details" {
+		t.Errorf("expected details to be kept from previous, got %s", merged.Details.String)
+	}
+}
+
+func TestPrepareValidatorDescriptionUpdate(t *testing.T) {
+	now := time.Now()
+	previous := NewValidatorDescriptionRow("cosmosvaloper1abc", "old-moniker", "", "", "", "", 1, now)
+
+	t.Run("valid update is merged", func(t *testing.T) {
+		incoming := NewValidatorDescriptionRow("cosmosvaloper1abc", DoNotModifyDesc, "new-identity", "", "", "", 2, now)
+
+		result, err := PrepareValidatorDescriptionUpdate(incoming, previous)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if result.Moniker.String != "old-moniker" {
+			t.Errorf("expected moniker to be kept from previous, got %s", result.Moniker.String)
+		}
+		if result.Identity.String != "new-identity" {
+			t.Errorf("expected identity to be updated, got %s", result.Identity.String)
+		}
+	})
+
+	t.Run("invalid update is rejected", func(t *testing.T) {
+		incoming := NewValidatorDescriptionRow("cosmosvaloper1abc", strings.Repeat("a", MaxMonikerLength+1), "", "", "", "", 2, now)
+
+		if _, err := PrepareValidatorDescriptionUpdate(incoming, previous); err == nil {
+			t.Errorf("expected an error for an over-length moniker, got none")
+		}
+	})
+}